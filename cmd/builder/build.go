@@ -8,35 +8,75 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+
+	"bitflora/patristics-builder/enrichment"
+	"bitflora/patristics-builder/translations"
 )
 
 var multiBlankRe = regexp.MustCompile(`\n{3,}`)
 
+// safeSeriesIDRe constrains series_id to characters safe to use verbatim as
+// a filename, since (unlike book_slug, which is always one of the fixed
+// slugs in data/books.tsv) series_id comes straight from the manuscripts
+// table with no such guarantee.
+var safeSeriesIDRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
 // ── JSON output types ─────────────────────────────────────────────────────────
 
-// bookRef is a single citation record within a book file.
+// bookRef is a single citation record within a book file. P is an int
+// index into the book's own passages array by default, or a shared
+// passage-store hash string when --shared-passages is set (see passages.go).
 type bookRef struct {
-	V *string `json:"v"`  // verse label, nil for chapter-level refs → JSON null
-	W int64   `json:"w"`  // manuscript ID (look up in index.works)
-	P int     `json:"p"`  // index into the book's passages array
+	V *string `json:"v"` // verse label, nil for chapter-level refs → JSON null
+	W int64   `json:"w"` // manuscript ID (look up in index.works)
+	P any     `json:"p"`
 }
 
 // bookChapter holds all refs for one chapter within a book file.
 type bookChapter struct {
 	Ch   int       `json:"ch"`
 	Refs []bookRef `json:"refs"`
+	// Verses maps verse number (as a string, for JSON object keys) to a
+	// sub-map of translation id → text, covering every verse cited by Refs
+	// in this chapter across all loaded translations. Omitted entirely when
+	// no --translations were loaded.
+	Verses map[string]map[string]string `json:"verses,omitempty"`
 }
 
 // bookPayload is the top-level structure for data/static/bible/{slug}.json.zst.
+// Passages is empty/omitted in --shared-passages mode, since passage text
+// lives in the global data/static/passages/ pool instead.
 type bookPayload struct {
 	Book     string        `json:"book"`
-	Passages []string      `json:"passages"`
+	Passages []string      `json:"passages,omitempty"`
 	Chapters []bookChapter `json:"chapters"`
 }
 
+// SeriesRef identifies a manuscript's place within a multi-volume
+// collection (Ante-Nicene Fathers, Patrologia Graeca, ...), embedded in
+// both workPayload and globalWork.
+type SeriesRef struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Index float64 `json:"index"`
+}
+
+// seriesRefOrNil builds a *SeriesRef from the nullable series_* columns on
+// manuscripts, returning nil when the manuscript has no series_id or when
+// series_id fails safeSeriesIDRe — buildSeries never writes a series file
+// for such an id, so referencing it here would point at a file that
+// doesn't exist.
+func seriesRefOrNil(id, name sql.NullString, index sql.NullFloat64) *SeriesRef {
+	if !id.Valid || id.String == "" || !safeSeriesIDRe.MatchString(id.String) {
+		return nil
+	}
+	return &SeriesRef{ID: id.String, Name: nullStringOr(name, id.String), Index: index.Float64}
+}
+
 // passKey uniquely identifies a passage span within a manuscript file.
 type passKey struct {
 	filename string
@@ -117,8 +157,30 @@ func queryDistinctBooks(db *sql.DB, onlyBook string) []string {
 	return result
 }
 
-// buildBook writes one book JSON.zst file containing all chapters. Returns total refs written.
-func buildBook(db *sql.DB, cache map[string][]rune, bookSlug string) int {
+// expandVerseRange turns a verse_start/verse_end pair into the list of
+// individual verse numbers it covers. Returns nil for chapter-level refs
+// (verse_start NULL).
+func expandVerseRange(start, end sql.NullInt64) []int {
+	if !start.Valid {
+		return nil
+	}
+	s := int(start.Int64)
+	e := s
+	if end.Valid && end.Int64 > start.Int64 {
+		e = int(end.Int64)
+	}
+	nums := make([]int, 0, e-s+1)
+	for v := s; v <= e; v++ {
+		nums = append(nums, v)
+	}
+	return nums
+}
+
+// buildBook writes one book JSON.zst file containing all chapters. Returns
+// total refs written. When store is non-nil, passages are deduplicated
+// through the global passage pool (see passages.go) instead of this
+// book's own embedded passages array.
+func buildBook(db *sql.DB, cache map[string][]rune, bookSlug string, reg *translations.Registry, store *passageStore) int {
 	book, ok := bySlug[bookSlug]
 	if !ok {
 		return 0
@@ -142,14 +204,17 @@ func buildBook(db *sql.DB, cache map[string][]rune, bookSlug string) int {
 	}
 	defer rows.Close()
 
-	// Passage deduplication pool for this book.
+	// Passage deduplication pool for this book (default mode), or a cache
+	// of already-stored hashes (--shared-passages mode).
 	passIdx := make(map[passKey]int)
+	passHash := make(map[passKey]string)
 	var passages []string
 
 	// Collect refs per chapter, building the passage pool as we go.
 	chapterMap := make(map[int][]bookRef)
 	var chapterOrder []int
 	seenCh := make(map[int]bool)
+	chapterVerseNums := make(map[int]map[int]bool)
 
 	for rows.Next() {
 		var chapter int
@@ -170,18 +235,44 @@ func buildBook(db *sql.DB, cache map[string][]rune, bookSlug string) int {
 		}
 
 		k := passKey{filename, int(passStart), int(passEnd)}
-		idx, found := passIdx[k]
-		if !found {
-			idx = len(passages)
-			passIdx[k] = idx
-			passages = append(passages, readPassage(cache, filename, int(passStart), int(passEnd)))
+		var p any
+		if store != nil {
+			hash, found := passHash[k]
+			if !found {
+				text := readPassage(cache, filename, int(passStart), int(passEnd))
+				var err error
+				hash, err = store.Put(text)
+				if err != nil {
+					log.Printf("storing passage for %s: %v", bookSlug, err)
+				} else {
+					passHash[k] = hash
+				}
+			}
+			p = hash
+		} else {
+			idx, found := passIdx[k]
+			if !found {
+				idx = len(passages)
+				passIdx[k] = idx
+				passages = append(passages, readPassage(cache, filename, int(passStart), int(passEnd)))
+			}
+			p = idx
 		}
 
 		chapterMap[chapter] = append(chapterMap[chapter], bookRef{
 			V: verseLabel(verseStart, verseEnd),
 			W: mID,
-			P: idx,
+			P: p,
 		})
+
+		if verseStart.Valid {
+			if chapterVerseNums[chapter] == nil {
+				chapterVerseNums[chapter] = make(map[int]bool)
+			}
+			for _, v := range expandVerseRange(verseStart, verseEnd) {
+				chapterVerseNums[chapter][v] = true
+			}
+		}
 	}
 
 	if len(chapterOrder) == 0 {
@@ -192,7 +283,15 @@ func buildBook(db *sql.DB, cache map[string][]rune, bookSlug string) int {
 	totalRefs := 0
 	for _, ch := range chapterOrder {
 		refs := chapterMap[ch]
-		chapters = append(chapters, bookChapter{Ch: ch, Refs: refs})
+		var verseNums []int
+		for v := range chapterVerseNums[ch] {
+			verseNums = append(verseNums, v)
+		}
+		chapters = append(chapters, bookChapter{
+			Ch:     ch,
+			Refs:   refs,
+			Verses: reg.VersesFor(bookSlug, ch, verseNums),
+		})
 		totalRefs += len(refs)
 	}
 
@@ -212,7 +311,7 @@ func buildBook(db *sql.DB, cache map[string][]rune, bookSlug string) int {
 
 // buildAll builds all book JSON.zst files in parallel using one goroutine per book,
 // bounded by a semaphore of size runtime.NumCPU().
-func buildAll(db *sql.DB, cache map[string][]rune, onlyBook string) {
+func buildAll(db *sql.DB, cache map[string][]rune, onlyBook string, reg *translations.Registry, store *passageStore) {
 	slugs := queryDistinctBooks(db, onlyBook)
 
 	sem := make(chan struct{}, runtime.NumCPU())
@@ -227,7 +326,7 @@ func buildAll(db *sql.DB, cache map[string][]rune, onlyBook string) {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			n := buildBook(db, cache, s)
+			n := buildBook(db, cache, s, reg, store)
 
 			mu.Lock()
 			if n > 0 {
@@ -245,31 +344,39 @@ func buildAll(db *sql.DB, cache map[string][]rune, onlyBook string) {
 // ── Works building ────────────────────────────────────────────────────────────
 
 // buildWorks writes one JSON.zst file per manuscript under data/static/manuscripts/.
-func buildWorks(db *sql.DB, cache map[string][]rune) {
+// enrichCache supplies optional CCEL/Wikidata/VIAF fields (see cmd/enrich); it
+// may be empty if no enrichment has been run. store is non-nil in
+// --shared-passages mode, in which case each workRef.P is a passage-store
+// hash instead of a local index and Passages is left empty (see passages.go).
+func buildWorks(db *sql.DB, cache map[string][]rune, enrichCache enrichment.Cache, store *passageStore) {
 	worksDir := filepath.Join(staticDir, "manuscripts")
 	if err := os.MkdirAll(worksDir, 0755); err != nil {
 		log.Fatalf("creating manuscripts dir: %v", err)
 	}
 
 	mRows, err := db.Query(
-		"SELECT id, author, title, year, filename, ccel_url FROM manuscripts ORDER BY id",
+		"SELECT id, author, title, year, filename, ccel_url, series_id, series_name, series_index FROM manuscripts ORDER BY id",
 	)
 	if err != nil {
 		log.Fatalf("querying manuscripts: %v", err)
 	}
 
 	type mRow struct {
-		id       int64
-		author   sql.NullString
-		title    sql.NullString
-		year     sql.NullInt64
-		filename string
-		ccelURL  sql.NullString
+		id          int64
+		author      sql.NullString
+		title       sql.NullString
+		year        sql.NullInt64
+		filename    string
+		ccelURL     sql.NullString
+		seriesID    sql.NullString
+		seriesName  sql.NullString
+		seriesIndex sql.NullFloat64
 	}
 	var manuscripts []mRow
 	for mRows.Next() {
 		var m mRow
-		if err := mRows.Scan(&m.id, &m.author, &m.title, &m.year, &m.filename, &m.ccelURL); err != nil {
+		if err := mRows.Scan(&m.id, &m.author, &m.title, &m.year, &m.filename, &m.ccelURL,
+			&m.seriesID, &m.seriesName, &m.seriesIndex); err != nil {
 			log.Fatalf("scanning manuscript row: %v", err)
 		}
 		manuscripts = append(manuscripts, m)
@@ -281,16 +388,19 @@ func buildWorks(db *sql.DB, cache map[string][]rune) {
 		BookSlug string  `json:"book_slug"`
 		Chapter  int     `json:"chapter"`
 		V        *string `json:"v"`
-		P        int     `json:"p"` // index into passages array
+		P        any     `json:"p"` // index into passages array, or a passage-store hash in --shared-passages mode
 	}
 	type workPayload struct {
-		ID       int64     `json:"id"`
-		Author   string    `json:"author"`
-		Title    string    `json:"title"`
-		Year     *int      `json:"year"`
-		CcelURL  *string   `json:"ccel_url,omitempty"`
-		Passages []string  `json:"passages"`
-		Refs     []workRef `json:"refs"`
+		ID       int64      `json:"id"`
+		Author   string     `json:"author"`
+		Title    string     `json:"title"`
+		Year     *int       `json:"year"`
+		CcelURL  *string    `json:"ccel_url,omitempty"`
+		Series   *SeriesRef `json:"series,omitempty"`
+		Passages []string   `json:"passages,omitempty"`
+		Refs     []workRef  `json:"refs"`
+
+		enrichment.Record
 	}
 
 	totalFiles := 0
@@ -308,8 +418,10 @@ func buildWorks(db *sql.DB, cache map[string][]rune) {
 			continue
 		}
 
-		// Passage deduplication pool for this manuscript.
+		// Passage deduplication pool for this manuscript (default mode), or a
+		// cache of already-stored hashes (--shared-passages mode).
 		passIdx := make(map[passKey]int)
+		passHash := make(map[passKey]string)
 		var passages []string
 
 		var refs []workRef
@@ -326,11 +438,28 @@ func buildWorks(db *sql.DB, cache map[string][]rune) {
 			}
 
 			k := passKey{m.filename, passStart, passEnd}
-			idx, found := passIdx[k]
-			if !found {
-				idx = len(passages)
-				passIdx[k] = idx
-				passages = append(passages, readPassage(cache, m.filename, passStart, passEnd))
+			var p any
+			if store != nil {
+				hash, found := passHash[k]
+				if !found {
+					text := readPassage(cache, m.filename, passStart, passEnd)
+					var err error
+					hash, err = store.Put(text)
+					if err != nil {
+						log.Printf("storing passage for manuscript %d: %v", m.id, err)
+					} else {
+						passHash[k] = hash
+					}
+				}
+				p = hash
+			} else {
+				idx, found := passIdx[k]
+				if !found {
+					idx = len(passages)
+					passIdx[k] = idx
+					passages = append(passages, readPassage(cache, m.filename, passStart, passEnd))
+				}
+				p = idx
 			}
 
 			refs = append(refs, workRef{
@@ -338,7 +467,7 @@ func buildWorks(db *sql.DB, cache map[string][]rune) {
 				BookSlug: bookSlug,
 				Chapter:  chapter,
 				V:        verseLabel(verseStart, verseEnd),
-				P:        idx,
+				P:        p,
 			})
 		}
 		refRows.Close()
@@ -353,8 +482,10 @@ func buildWorks(db *sql.DB, cache map[string][]rune) {
 			Title:    nullStringOr(m.title, m.filename),
 			Year:     nullInt64Ptr(m.year),
 			CcelURL:  nullStringPtr(m.ccelURL),
+			Series:   seriesRefOrNil(m.seriesID, m.seriesName, m.seriesIndex),
 			Passages: passages,
 			Refs:     refs,
+			Record:   enrichCache[m.id],
 		}
 
 		outPath := filepath.Join(worksDir, fmt.Sprintf("%d.json.zst", m.id))
@@ -371,7 +502,7 @@ func buildWorks(db *sql.DB, cache map[string][]rune) {
 // ── Index building ────────────────────────────────────────────────────────────
 
 // buildIndex writes data/static/index.json.zst.
-func buildIndex(db *sql.DB, onlyBook string) {
+func buildIndex(db *sql.DB, onlyBook string, reg *translations.Registry, groupBySeries bool, enrichCache enrichment.Cache) {
 	// Per-chapter reference counts broken down by category
 	chRows, err := db.Query(`
 		SELECT vr.book_slug, vr.chapter, COALESCE(m.category, 'Other') AS cat, COUNT(*) AS n
@@ -409,6 +540,7 @@ func buildIndex(db *sql.DB, onlyBook string) {
 	// so every entry in the index has a corresponding work file in data/static/manuscripts/.
 	wRows, err := db.Query(`
 		SELECT m.id, m.author, m.title, m.year, m.filename, m.category, m.ccel_url,
+		       m.series_id, m.series_name, m.series_index,
 		       COUNT(vr.id) AS ref_count
 		FROM manuscripts m
 		JOIN verse_refs vr ON vr.manuscript_id = m.id
@@ -419,22 +551,28 @@ func buildIndex(db *sql.DB, onlyBook string) {
 		log.Fatalf("querying global works: %v", err)
 	}
 	type globalWork struct {
-		ID       int64   `json:"id"`
-		Author   string  `json:"author"`
-		Title    string  `json:"title"`
-		Year     *int    `json:"year"`
-		CcelURL  *string `json:"ccel_url,omitempty"`
-		RefCount int     `json:"ref_count"`
-		Category string  `json:"category"`
+		ID       int64      `json:"id"`
+		Author   string     `json:"author"`
+		Title    string     `json:"title"`
+		Year     *int       `json:"year"`
+		CcelURL  *string    `json:"ccel_url,omitempty"`
+		Series   *SeriesRef `json:"series,omitempty"`
+		RefCount int        `json:"ref_count"`
+		Category string     `json:"category"`
+
+		enrichment.Record
 	}
 	var globalWorks []globalWork
 	for wRows.Next() {
 		var id int64
 		var author, title, category, ccelURL sql.NullString
+		var seriesID, seriesName sql.NullString
+		var seriesIndex sql.NullFloat64
 		var year sql.NullInt64
 		var filename string
 		var refCount int
-		if err := wRows.Scan(&id, &author, &title, &year, &filename, &category, &ccelURL, &refCount); err != nil {
+		if err := wRows.Scan(&id, &author, &title, &year, &filename, &category, &ccelURL,
+			&seriesID, &seriesName, &seriesIndex, &refCount); err != nil {
 			log.Fatalf("scanning global work: %v", err)
 		}
 		globalWorks = append(globalWorks, globalWork{
@@ -443,12 +581,43 @@ func buildIndex(db *sql.DB, onlyBook string) {
 			Title:    nullStringOr(title, filename),
 			Year:     nullInt64Ptr(year),
 			CcelURL:  nullStringPtr(ccelURL),
+			Series:   seriesRefOrNil(seriesID, seriesName, seriesIndex),
 			RefCount: refCount,
 			Category: nullStringOr(category, "Other"),
+			Record:   enrichCache[id],
 		})
 	}
 	wRows.Close()
 
+	if groupBySeries {
+		sort.SliceStable(globalWorks, func(i, j int) bool {
+			a, b := globalWorks[i], globalWorks[j]
+			aName, bName := "", ""
+			if a.Series != nil {
+				aName = a.Series.Name
+			}
+			if b.Series != nil {
+				bName = b.Series.Name
+			}
+			// Works outside any series sort after every series group,
+			// alphabetically among themselves (the SQL ORDER BY already
+			// gave us author/title order to fall back on).
+			if aName != bName {
+				if aName == "" {
+					return false
+				}
+				if bName == "" {
+					return true
+				}
+				return aName < bName
+			}
+			if a.Series != nil && b.Series != nil && a.Series.Index != b.Series.Index {
+				return a.Series.Index < b.Series.Index
+			}
+			return i < j
+		})
+	}
+
 	type chapterEntry struct {
 		Ch    int            `json:"ch"`
 		Count int            `json:"count"`
@@ -486,11 +655,15 @@ func buildIndex(db *sql.DB, onlyBook string) {
 		}
 	}
 
+	seriesOut := buildSeries(db)
+
 	type indexPayload struct {
-		Books []bookEntry  `json:"books"`
-		Works []globalWork `json:"works"`
+		Books        []bookEntry         `json:"books"`
+		Works        []globalWork        `json:"works"`
+		Translations []translations.Meta `json:"translations,omitempty"`
+		Series       []seriesSummary     `json:"series,omitempty"`
 	}
-	payload := indexPayload{Books: booksOut, Works: globalWorks}
+	payload := indexPayload{Books: booksOut, Works: globalWorks, Translations: reg.Metas(), Series: seriesOut}
 
 	if err := os.MkdirAll(staticDir, 0755); err != nil {
 		log.Fatalf("creating static dir: %v", err)
@@ -501,3 +674,96 @@ func buildIndex(db *sql.DB, onlyBook string) {
 	}
 	fmt.Printf("Wrote %s  (%d books with references)\n", outPath, len(booksOut))
 }
+
+// ── Series building ──────────────────────────────────────────────────────────
+
+// seriesMember is one manuscript's entry in a series' member list.
+type seriesMember struct {
+	ID       int64   `json:"id"`
+	Author   string  `json:"author"`
+	Title    string  `json:"title"`
+	Index    float64 `json:"index"`
+	RefCount int     `json:"ref_count"`
+}
+
+// seriesPayload is the top-level structure for data/static/series/{id}.json.zst.
+type seriesPayload struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Members []seriesMember `json:"members"`
+}
+
+// seriesSummary is a series' entry in index.json.zst's top-level series list.
+type seriesSummary struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Members int    `json:"members"`
+}
+
+// buildSeries writes one data/static/series/{id}.json.zst per distinct
+// series_id, each listing its member works ordered by series_index, and
+// returns the summary list for index.json.zst.
+func buildSeries(db *sql.DB) []seriesSummary {
+	rows, err := db.Query(`
+		SELECT m.series_id, m.series_name, m.id, m.author, m.title, m.series_index,
+		       COUNT(vr.id) AS ref_count
+		FROM manuscripts m
+		JOIN verse_refs vr ON vr.manuscript_id = m.id
+		WHERE m.series_id IS NOT NULL AND m.series_id != ''
+		GROUP BY m.id
+		ORDER BY m.series_id, m.series_index
+	`)
+	if err != nil {
+		log.Fatalf("querying series: %v", err)
+	}
+	defer rows.Close()
+
+	var seriesOrder []string
+	seriesNames := make(map[string]string)
+	seriesMembers := make(map[string][]seriesMember)
+
+	for rows.Next() {
+		var seriesID, seriesName, author, title sql.NullString
+		var id int64
+		var index sql.NullFloat64
+		var refCount int
+		if err := rows.Scan(&seriesID, &seriesName, &id, &author, &title, &index, &refCount); err != nil {
+			log.Fatalf("scanning series member: %v", err)
+		}
+		sid := seriesID.String
+		if !safeSeriesIDRe.MatchString(sid) {
+			log.Printf("skipping series %q: series_id is not safe to use as a filename", sid)
+			continue
+		}
+		if _, ok := seriesMembers[sid]; !ok {
+			seriesOrder = append(seriesOrder, sid)
+			seriesNames[sid] = nullStringOr(seriesName, sid)
+		}
+		seriesMembers[sid] = append(seriesMembers[sid], seriesMember{
+			ID:       id,
+			Author:   nullStringOr(author, "Unknown"),
+			Title:    nullStringOr(title, "Untitled"),
+			Index:    index.Float64,
+			RefCount: refCount,
+		})
+	}
+
+	if len(seriesOrder) == 0 {
+		return nil
+	}
+
+	dir := filepath.Join(staticDir, "series")
+	var summaries []seriesSummary
+	for _, sid := range seriesOrder {
+		members := seriesMembers[sid]
+		payload := seriesPayload{ID: sid, Name: seriesNames[sid], Members: members}
+		outPath := filepath.Join(dir, fmt.Sprintf("%s.json.zst", sid))
+		if err := writeZstJSON(outPath, payload); err != nil {
+			log.Printf("writing %s: %v", outPath, err)
+			continue
+		}
+		summaries = append(summaries, seriesSummary{ID: sid, Name: seriesNames[sid], Members: len(members)})
+	}
+	fmt.Printf("Built %d series file(s).\n", len(summaries))
+	return summaries
+}
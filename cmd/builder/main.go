@@ -7,11 +7,18 @@ for the viewer. Run from the repository root:
   go run ./cmd/builder               # build everything
   go run ./cmd/builder --book romans # build only one book
   go run ./cmd/builder --clean       # delete data/static/ before building
+  go run ./cmd/builder --query "Jude 4; Rom 1:1-3,7" # print DB matches, build nothing
+  go run ./cmd/builder --init-db     # create an empty data/patristics.db from the embedded schema
+  go run ./cmd/builder --shared-passages # dedupe passage text into a global content-addressed pool
+  go run ./cmd/builder --translations kjv.tsv,asv.tsv # attach parallel verse-per-row translations
+  go run ./cmd/builder --group-by-series # sort index.json.zst's work list by series
 
 Outputs:
   data/static/index.json.zst                      — book list with per-chapter ref counts
   data/static/bible/{book-slug}/{ch}.json.zst     — all references for a chapter
   data/static/manuscripts/{id}.json.zst           — all references from a single work
+  data/static/series/{id}.json.zst                — member works of a series, ordered by series_index
+  data/static/passages/{hash[:2]}/{hash}.json.zst — shared passage text (--shared-passages only)
 */
 package main
 
@@ -29,15 +36,19 @@ import (
 
 	"github.com/klauspost/compress/zstd"
 	_ "modernc.org/sqlite"
+
+	"bitflora/patristics-builder/enrichment"
+	"bitflora/patristics-builder/translations"
 )
 
 const maxPassageChars = 8000
 
 var (
-	repoRoot       = mustCwd()
-	manuscriptsDir = filepath.Join(repoRoot, "manuscripts")
-	staticDir      = filepath.Join(repoRoot, "data", "static")
-	dbPath         = filepath.Join(repoRoot, "data", "patristics.db")
+	repoRoot        = mustCwd()
+	manuscriptsDir  = filepath.Join(repoRoot, "manuscripts")
+	staticDir       = filepath.Join(repoRoot, "data", "static")
+	dbPath          = filepath.Join(repoRoot, "data", "patristics.db")
+	enrichCachePath = filepath.Join(repoRoot, "data", "enrichment_cache.json")
 )
 
 func mustCwd() string {
@@ -51,15 +62,36 @@ func mustCwd() string {
 func main() {
 	bookFlag := flag.String("book", "", "Only build files for this book slug")
 	cleanFlag := flag.Bool("clean", false, "Delete data/static/ before building")
+	translationsFlag := flag.String("translations", "", "Comma-separated list of verse-per-row TSV files to load as parallel translations (id inferred from filename, e.g. kjv.tsv -> \"kjv\")")
+	queryFlag := flag.String("query", "", "Parse a citation string (e.g. \"Jude 4; Rom 1:1-3,7\") and print matching rows from the database, instead of building")
+	initDBFlag := flag.Bool("init-db", false, "Create data/patristics.db from the embedded schema if it doesn't already exist")
+	groupBySeriesFlag := flag.Bool("group-by-series", false, "Sort index.json.zst's work list by series (falls back to author/title alphabetical for works without a series)")
+	sharedPassagesFlag := flag.Bool("shared-passages", false, "Write passage text to a global content-addressed data/static/passages/ pool instead of duplicating it into each book/work file")
 	flag.Parse()
 
+	if *initDBFlag {
+		if err := initDB(dbPath); err != nil {
+			log.Fatalf("initializing database: %v", err)
+		}
+	}
+
+	if *queryFlag != "" {
+		runQuery(*queryFlag)
+		return
+	}
+
+	translationReg, err := loadTranslations(*translationsFlag)
+	if err != nil {
+		log.Fatalf("loading translations: %v", err)
+	}
+
 	if *cleanFlag {
 		os.RemoveAll(staticDir)
 		fmt.Printf("Removed %s\n", staticDir)
 	}
 
 	if _, err := os.Stat(dbPath); err != nil {
-		log.Fatalf("Database not found at %s. Run parser.py first.", dbPath)
+		log.Fatalf("Database not found at %s. Run parser.py first, or pass --init-db.", dbPath)
 	}
 
 	cache, err := loadCache()
@@ -68,17 +100,60 @@ func main() {
 	}
 	fmt.Printf("Loaded %d manuscript files into memory.\n", len(cache))
 
+	enrichCache, err := enrichment.LoadCache(enrichCachePath)
+	if err != nil {
+		log.Fatalf("loading enrichment cache: %v", err)
+	}
+	if len(enrichCache) > 0 {
+		fmt.Printf("Loaded %d enriched manuscript record(s) from %s\n", len(enrichCache), enrichCachePath)
+	}
+
 	db := openDB(dbPath)
 	defer db.Close()
 
-	buildAll(db, cache, *bookFlag)
-	buildIndex(db, *bookFlag)
+	var store *passageStore
+	if *sharedPassagesFlag {
+		store = newPassageStore()
+	}
+
+	buildAll(db, cache, *bookFlag, translationReg, store)
+	buildIndex(db, *bookFlag, translationReg, *groupBySeriesFlag, enrichCache)
 	if *bookFlag == "" {
-		buildWorks(db, cache)
+		buildWorks(db, cache, enrichCache, store)
+	}
+	if store != nil {
+		if err := store.Manifest(); err != nil {
+			log.Printf("writing passage manifest: %v", err)
+		}
 	}
 	cleanupUncompressed()
 }
 
+// loadTranslations parses the --translations flag (a comma-separated list
+// of TSV paths) and loads each into the returned registry. The short id
+// used to register each translation is the file's base name with its
+// extension stripped, e.g. "data/translations/kjv.tsv" -> "kjv".
+func loadTranslations(flagValue string) (*translations.Registry, error) {
+	reg := translations.NewRegistry()
+	if flagValue == "" {
+		return reg, nil
+	}
+	for _, path := range strings.Split(flagValue, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		store, err := translations.Load(id, path)
+		if err != nil {
+			return nil, fmt.Errorf("loading translation %q from %s: %w", id, path, err)
+		}
+		reg.Add(store)
+		fmt.Printf("Loaded translation %q from %s\n", id, path)
+	}
+	return reg, nil
+}
+
 // loadCache reads all manuscript .txt files into memory as []rune slices.
 // Offsets stored in the DB are Python Unicode code point offsets (equivalent
 // to rune indices), so we store []rune for O(1) slicing.
@@ -168,3 +243,12 @@ func nullInt64Ptr(n sql.NullInt64) *int {
 	v := int(n.Int64)
 	return &v
 }
+
+// nullStringPtr returns a pointer to the string value, or nil if the value is NULL.
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	v := s.String
+	return &v
+}
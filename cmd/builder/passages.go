@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// passageStore is the process-wide content-addressed passage pool used
+// when --shared-passages is set. Each unique passage (by SHA-256 of its
+// text) is written to data/static/passages/{2-hex-prefix}/{hash}.json.zst
+// exactly once, shared across every book and manuscript file that cites
+// it, instead of being duplicated into each file's own passages array.
+//
+// The hash scheme and manifest are a stable contract: viewers may rely on
+// data/static/passages/{hash[:2]}/{hash}.json.zst existing for every hash
+// listed in manifest.json.zst, to lazy-load passage text on hover.
+type passageStore struct {
+	mu      sync.Mutex
+	written map[string]bool
+}
+
+func newPassageStore() *passageStore {
+	return &passageStore{written: make(map[string]bool)}
+}
+
+// passagePayload is the structure of a single passages/{hash}.json.zst file.
+type passagePayload struct {
+	Text string `json:"text"`
+}
+
+// Put writes text's shard file the first time it's seen and returns its
+// hash id — the value that goes into bookRef.P / workRef.P in
+// --shared-passages mode. Safe for concurrent use. A hash is only marked
+// written once writeZstJSON actually succeeds, so a failed write is
+// retried (rather than silently treated as done) and never ends up listed
+// in manifest.json.zst without a file to back it.
+func (s *passageStore) Put(text string) (string, error) {
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written[hash] {
+		return hash, nil
+	}
+
+	outPath := filepath.Join(staticDir, "passages", hash[:2], hash+".json.zst")
+	if err := writeZstJSON(outPath, passagePayload{Text: text}); err != nil {
+		return "", fmt.Errorf("writing passage %s: %w", hash, err)
+	}
+	s.written[hash] = true
+	return hash, nil
+}
+
+// Manifest writes data/static/passages/manifest.json.zst: the sorted list
+// of every hash written this run, for cache warming.
+func (s *passageStore) Manifest() error {
+	s.mu.Lock()
+	hashes := make([]string, 0, len(s.written))
+	for h := range s.written {
+		hashes = append(hashes, h)
+	}
+	s.mu.Unlock()
+	sort.Strings(hashes)
+
+	outPath := filepath.Join(staticDir, "passages", "manifest.json.zst")
+	return writeZstJSON(outPath, struct {
+		Hashes []string `json:"hashes"`
+	}{Hashes: hashes})
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"bitflora/patristics-builder/refparse"
+)
+
+// bookInfos converts the canonical books table into the shape refparse
+// needs to resolve citations.
+func bookInfos() []refparse.BookInfo {
+	infos := make([]refparse.BookInfo, len(books))
+	for i, b := range books {
+		infos[i] = refparse.BookInfo{Slug: b.Slug, Name: b.Name, Chapters: b.Chapters}
+	}
+	return infos
+}
+
+// runQuery implements `--query "Jude 4; Rom 1:1-3,7"`: parse the citation
+// string with refparse, then print every matching verse_refs row from the
+// database. It does not build any static output.
+func runQuery(query string) {
+	table := refparse.NewTable(bookInfos())
+	refs, err := table.Parse(query)
+	if err != nil {
+		log.Fatalf("parsing --query: %v", err)
+	}
+
+	if _, err := os.Stat(dbPath); err != nil {
+		log.Fatalf("Database not found at %s. Run parser.py first.", dbPath)
+	}
+
+	db := openDB(dbPath)
+	defer db.Close()
+
+	for _, ref := range refs {
+		printQueryMatches(db, ref)
+	}
+}
+
+// printQueryMatches prints every citation in the database falling within
+// one parsed ref's book/chapter/verse span.
+func printQueryMatches(db *sql.DB, ref refparse.ParsedRef) {
+	endChapter := ref.EndChapter
+	if endChapter == 0 {
+		endChapter = ref.Chapter
+	}
+
+	rows, err := db.Query(`
+		SELECT vr.chapter, vr.verse_start, vr.verse_end, m.author, m.title
+		FROM verse_refs vr
+		JOIN manuscripts m ON m.id = vr.manuscript_id
+		WHERE vr.book_slug = ? AND vr.chapter BETWEEN ? AND ?
+		ORDER BY vr.chapter, vr.verse_start NULLS LAST, m.author, m.title
+	`, ref.BookSlug, ref.Chapter, endChapter)
+	if err != nil {
+		log.Printf("querying %s: %v", ref.BookSlug, err)
+		return
+	}
+	defer rows.Close()
+
+	book := bySlug[ref.BookSlug]
+	n := 0
+	for rows.Next() {
+		var chapter int
+		var verseStart, verseEnd sql.NullInt64
+		var author, title sql.NullString
+		if err := rows.Scan(&chapter, &verseStart, &verseEnd, &author, &title); err != nil {
+			log.Printf("scanning match: %v", err)
+			continue
+		}
+		if !refCoversVerse(ref, chapter, endChapter, verseStart, verseEnd) {
+			continue
+		}
+		n++
+		fmt.Printf("%s %d%s — %s, %s\n", book.Name, chapter, verseLabelSuffix(verseStart, verseEnd),
+			nullStringOr(author, "Unknown"), nullStringOr(title, "Untitled"))
+	}
+	if n == 0 {
+		fmt.Printf("%s — no citations found.\n", ref.BookSlug)
+	}
+}
+
+// refCoversVerse reports whether a verse_refs row falls within the
+// requested span. Chapter-level rows (verse_start NULL) always count.
+// Boundary chapters of a verse-qualified ref are clipped to VerseStart/
+// VerseEnd; interior chapters of a cross-chapter range are unrestricted.
+func refCoversVerse(ref refparse.ParsedRef, chapter, endChapter int, verseStart, verseEnd sql.NullInt64) bool {
+	if ref.VerseStart == 0 {
+		return true
+	}
+	if !verseStart.Valid {
+		return true
+	}
+	lo, hi := int64(0), int64(1<<62)
+	if chapter == ref.Chapter {
+		lo = int64(ref.VerseStart)
+	}
+	if chapter == endChapter {
+		hi = int64(ref.VerseEnd)
+	}
+	rowStart, rowEnd := verseStart.Int64, verseStart.Int64
+	if verseEnd.Valid {
+		rowEnd = verseEnd.Int64
+	}
+	return rowStart <= hi && rowEnd >= lo
+}
+
+// verseLabelSuffix formats a chapter's verse_start/verse_end for display.
+func verseLabelSuffix(start, end sql.NullInt64) string {
+	if label := verseLabel(start, end); label != nil {
+		return ":" + *label
+	}
+	return ""
+}
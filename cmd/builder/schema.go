@@ -0,0 +1,38 @@
+package main
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//go:embed data/schema.sql
+var schemaSQL string
+
+// initDB creates an empty SQLite database at path from the embedded
+// schema, unless a file already exists there. Run via --init-db so
+// contributors (and downstream users who only want the static-file
+// pipeline) can produce a usable data/patristics.db without parser.py.
+func initDB(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		fmt.Printf("Database already exists at %s; leaving it untouched.\n", path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(schemaSQL); err != nil {
+		return fmt.Errorf("applying embedded schema: %w", err)
+	}
+	fmt.Printf("Initialized empty database at %s from embedded schema.\n", path)
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+cmd/enrich fetches supplementary bibliographic metadata for manuscripts
+from external providers (CCEL, Wikidata, VIAF) and caches it to a local
+JSON file for the builder to merge in. Run from the repository root:
+
+  go run ./cmd/enrich                       # enrich every eligible manuscript
+  go run ./cmd/enrich --providers wikidata  # only query Wikidata
+  go run ./cmd/enrich --dry-run             # print proposed updates, write nothing
+
+A manuscript is eligible once it has a ccel_url or a non-empty author
+name. Results are keyed by manuscript id in data/enrichment_cache.json;
+re-running only fetches manuscripts not already in the cache, so
+enrichment is idempotent and the cache can be rebuilt offline from a
+prior run without hitting the network again.
+*/
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"bitflora/patristics-builder/enrichment"
+)
+
+var repoRoot = mustCwd()
+
+func mustCwd() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("getting working directory: %v", err)
+	}
+	return dir
+}
+
+func main() {
+	dbFlag := flag.String("db", filepath.Join(repoRoot, "data", "patristics.db"), "Path to the SQLite database")
+	cacheFlag := flag.String("cache", filepath.Join(repoRoot, "data", "enrichment_cache.json"), "Path to the enrichment cache JSON file")
+	providersFlag := flag.String("providers", "ccel,wikidata,viaf", "Comma-separated list of providers to query (ccel, wikidata, viaf)")
+	dryRun := flag.Bool("dry-run", false, "Print proposed updates without writing the cache")
+	flag.Parse()
+
+	if _, err := os.Stat(*dbFlag); err != nil {
+		log.Fatalf("Database not found at %s. Run parser.py or the builder's --init-db first.", *dbFlag)
+	}
+
+	db, err := sql.Open("sqlite", *dbFlag)
+	if err != nil {
+		log.Fatalf("opening database: %v", err)
+	}
+	defer db.Close()
+
+	manuscripts, err := queryManuscripts(db)
+	if err != nil {
+		log.Fatalf("querying manuscripts: %v", err)
+	}
+
+	cache, err := enrichment.LoadCache(*cacheFlag)
+	if err != nil {
+		log.Fatalf("loading enrichment cache: %v", err)
+	}
+
+	providers := buildProviders(strings.Split(*providersFlag, ","))
+	if len(providers) == 0 {
+		log.Fatalf("no known providers in --providers %q (want ccel, wikidata, viaf)", *providersFlag)
+	}
+
+	updated := 0
+	for _, m := range manuscripts {
+		if _, cached := cache[m.ID]; cached {
+			continue
+		}
+		if m.CcelURL == "" && m.Author == "" {
+			continue
+		}
+
+		rec := enrichOne(m, providers)
+		if rec.IsZero() {
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("[dry-run] #%d %s: %+v\n", m.ID, m.Author, rec)
+		} else {
+			cache[m.ID] = rec
+		}
+		updated++
+	}
+
+	if *dryRun {
+		fmt.Printf("\n%d manuscript(s) would be enriched (dry run, cache not written).\n", updated)
+		return
+	}
+
+	if err := cache.Save(*cacheFlag); err != nil {
+		log.Fatalf("saving enrichment cache: %v", err)
+	}
+	fmt.Printf("Enriched %d manuscript(s); cache now has %d entries at %s\n", updated, len(cache), *cacheFlag)
+}
+
+// manuscript is the subset of a manuscripts row cmd/enrich needs to
+// query providers.
+type manuscript struct {
+	ID      int64
+	Author  string
+	CcelURL string
+}
+
+func queryManuscripts(db *sql.DB) ([]manuscript, error) {
+	rows, err := db.Query(`SELECT id, COALESCE(author, ''), COALESCE(ccel_url, '') FROM manuscripts ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []manuscript
+	for rows.Next() {
+		var m manuscript
+		if err := rows.Scan(&m.ID, &m.Author, &m.CcelURL); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// enrichOne queries every provider in order and merges their results,
+// with earlier providers' fields winning on conflict (so --providers
+// order doubles as a priority order). A provider error is logged and
+// skipped rather than aborting the whole manuscript.
+func enrichOne(m manuscript, providers []provider) enrichment.Record {
+	var rec enrichment.Record
+	for _, p := range providers {
+		p.rateLimiter().wait()
+		found, err := p.enrich(m)
+		if err != nil {
+			log.Printf("  %s: %s (#%d): %v", p.name(), m.Author, m.ID, err)
+			continue
+		}
+		rec = mergeRecord(rec, found)
+	}
+	return rec
+}
+
+func mergeRecord(dst, src enrichment.Record) enrichment.Record {
+	if dst.AuthorVIAF == "" {
+		dst.AuthorVIAF = src.AuthorVIAF
+	}
+	if dst.AuthorWikidata == "" {
+		dst.AuthorWikidata = src.AuthorWikidata
+	}
+	if dst.AuthorBirth == nil {
+		dst.AuthorBirth = src.AuthorBirth
+	}
+	if dst.AuthorDeath == nil {
+		dst.AuthorDeath = src.AuthorDeath
+	}
+	if dst.OriginalLang == "" {
+		dst.OriginalLang = src.OriginalLang
+	}
+	if dst.Translator == "" {
+		dst.Translator = src.Translator
+	}
+	if dst.Bio == "" {
+		dst.Bio = src.Bio
+	}
+	return dst
+}
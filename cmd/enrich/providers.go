@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bitflora/patristics-builder/enrichment"
+)
+
+// provider fetches whatever fields it can for a manuscript. Implementations
+// should leave a Record field zero rather than guessing when a lookup
+// comes back empty.
+type provider interface {
+	name() string
+	rateLimiter() *rateLimiter
+	enrich(m manuscript) (enrichment.Record, error)
+}
+
+// rateLimiter enforces a minimum gap between requests to one provider, so
+// a full enrichment run doesn't hammer CCEL/Wikidata/VIAF.
+type rateLimiter struct {
+	mu       sync.Mutex
+	last     time.Time
+	minDelay time.Duration
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	return &rateLimiter{minDelay: time.Duration(float64(time.Second) / perSecond)}
+}
+
+func (r *rateLimiter) wait() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if since := time.Since(r.last); since < r.minDelay {
+		time.Sleep(r.minDelay - since)
+	}
+	r.last = time.Now()
+}
+
+// buildProviders resolves --providers into provider implementations,
+// silently skipping unknown names (main.go errors out if the result is
+// empty).
+func buildProviders(names []string) []provider {
+	var out []provider
+	for _, n := range names {
+		switch n {
+		case "ccel":
+			out = append(out, newCCELProvider())
+		case "wikidata":
+			out = append(out, newWikidataProvider())
+		case "viaf":
+			out = append(out, newVIAFProvider())
+		}
+	}
+	return out
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// ── CCEL ──────────────────────────────────────────────────────────────────────
+
+// ccelProvider scrapes CCEL's per-author RDF feed for birth/death years
+// and a bio blurb. CCEL's RDF is loosely structured, so this is a
+// best-effort regex extraction rather than a full RDF parser.
+type ccelProvider struct{ rl *rateLimiter }
+
+func newCCELProvider() *ccelProvider { return &ccelProvider{rl: newRateLimiter(1)} }
+
+func (p *ccelProvider) name() string              { return "ccel" }
+func (p *ccelProvider) rateLimiter() *rateLimiter { return p.rl }
+
+var (
+	ccelBirthRe = regexp.MustCompile(`<birth[^>]*>\s*(\d{3,4})`)
+	ccelDeathRe = regexp.MustCompile(`<death[^>]*>\s*(\d{3,4})`)
+	ccelBioRe   = regexp.MustCompile(`<description[^>]*>([^<]{20,400})</description>`)
+)
+
+func (p *ccelProvider) enrich(m manuscript) (enrichment.Record, error) {
+	if m.CcelURL == "" {
+		return enrichment.Record{}, nil
+	}
+	body, err := fetch(m.CcelURL + ".rdf")
+	if err != nil {
+		return enrichment.Record{}, fmt.Errorf("ccel: %w", err)
+	}
+	var rec enrichment.Record
+	if match := ccelBirthRe.FindSubmatch(body); match != nil {
+		rec.AuthorBirth = atoiPtr(string(match[1]))
+	}
+	if match := ccelDeathRe.FindSubmatch(body); match != nil {
+		rec.AuthorDeath = atoiPtr(string(match[1]))
+	}
+	if match := ccelBioRe.FindSubmatch(body); match != nil {
+		rec.Bio = string(match[1])
+	}
+	return rec, nil
+}
+
+// ── Wikidata ──────────────────────────────────────────────────────────────────
+
+// wikidataProvider runs a small SPARQL query matching the manuscript's
+// author name against an entity's rdfs:label, pulling the Wikidata QID,
+// birth/death years, and native language.
+type wikidataProvider struct{ rl *rateLimiter }
+
+func newWikidataProvider() *wikidataProvider { return &wikidataProvider{rl: newRateLimiter(1)} }
+
+func (p *wikidataProvider) name() string              { return "wikidata" }
+func (p *wikidataProvider) rateLimiter() *rateLimiter { return p.rl }
+
+const wikidataQueryTemplate = `
+SELECT ?person ?birth ?death ?langLabel WHERE {
+  ?person rdfs:label "%s"@en.
+  ?person wdt:P31 wd:Q5.
+  OPTIONAL { ?person wdt:P569 ?birth. }
+  OPTIONAL { ?person wdt:P570 ?death. }
+  OPTIONAL { ?person wdt:P103 ?lang. }
+  SERVICE wikibase:label { bd:serviceParam wikibase:language "en". }
+} LIMIT 1`
+
+type wikidataResponse struct {
+	Results struct {
+		Bindings []map[string]struct {
+			Value string `json:"value"`
+		} `json:"bindings"`
+	} `json:"results"`
+}
+
+func (p *wikidataProvider) enrich(m manuscript) (enrichment.Record, error) {
+	if m.Author == "" {
+		return enrichment.Record{}, nil
+	}
+	query := fmt.Sprintf(wikidataQueryTemplate, m.Author)
+	endpoint := "https://query.wikidata.org/sparql?format=json&query=" + url.QueryEscape(query)
+
+	body, err := fetch(endpoint)
+	if err != nil {
+		return enrichment.Record{}, fmt.Errorf("wikidata: %w", err)
+	}
+	var resp wikidataResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return enrichment.Record{}, fmt.Errorf("wikidata: decoding response: %w", err)
+	}
+	if len(resp.Results.Bindings) == 0 {
+		return enrichment.Record{}, nil
+	}
+	row := resp.Results.Bindings[0]
+
+	var rec enrichment.Record
+	if person, ok := row["person"]; ok {
+		rec.AuthorWikidata = qidFromURI(person.Value)
+	}
+	if birth, ok := row["birth"]; ok {
+		rec.AuthorBirth = yearFromWikidataDate(birth.Value)
+	}
+	if death, ok := row["death"]; ok {
+		rec.AuthorDeath = yearFromWikidataDate(death.Value)
+	}
+	if lang, ok := row["langLabel"]; ok {
+		rec.OriginalLang = lang.Value
+	}
+	return rec, nil
+}
+
+var wikidataEntityRe = regexp.MustCompile(`(Q\d+)$`)
+
+func qidFromURI(uri string) string {
+	if m := wikidataEntityRe.FindStringSubmatch(uri); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// ── VIAF ──────────────────────────────────────────────────────────────────────
+
+// viafProvider uses VIAF's autosuggest endpoint to find an author's VIAF
+// cluster id — useful as a stable cross-reference even when Wikidata
+// doesn't have the author.
+type viafProvider struct{ rl *rateLimiter }
+
+func newVIAFProvider() *viafProvider { return &viafProvider{rl: newRateLimiter(2)} }
+
+func (p *viafProvider) name() string              { return "viaf" }
+func (p *viafProvider) rateLimiter() *rateLimiter { return p.rl }
+
+type viafSuggestResponse struct {
+	Result []struct {
+		ViafID string `json:"viafid"`
+		Term   string `json:"term"`
+	} `json:"result"`
+}
+
+func (p *viafProvider) enrich(m manuscript) (enrichment.Record, error) {
+	if m.Author == "" {
+		return enrichment.Record{}, nil
+	}
+	endpoint := "https://viaf.org/viaf/AutoSuggest?query=" + url.QueryEscape(m.Author)
+
+	body, err := fetch(endpoint)
+	if err != nil {
+		return enrichment.Record{}, fmt.Errorf("viaf: %w", err)
+	}
+	var resp viafSuggestResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return enrichment.Record{}, fmt.Errorf("viaf: decoding response: %w", err)
+	}
+	if len(resp.Result) == 0 {
+		return enrichment.Record{}, nil
+	}
+	return enrichment.Record{AuthorVIAF: resp.Result[0].ViafID}, nil
+}
+
+// ── shared helpers ──────────────────────────────────────────────────────────
+
+func fetch(endpoint string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "patristics-builder-enrich/1.0")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %s", endpoint, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func atoiPtr(s string) *int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// yearFromWikidataDate extracts the year out of a Wikidata xsd:dateTime
+// value (e.g. "+1225-01-28T00:00:00Z" or, for BCE dates, "-0044-03-15T...Z").
+// It returns nil rather than panicking on anything that isn't a
+// recognizable leading year, since Wikidata time values can also be bare
+// "somevalue" placeholders for imprecise dates.
+func yearFromWikidataDate(s string) *int {
+	s = strings.TrimPrefix(s, "+")
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	yearStr := s
+	if i := strings.IndexByte(s, '-'); i > 0 {
+		yearStr = s[:i]
+	}
+	if yearStr == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(yearStr)
+	if err != nil {
+		return nil
+	}
+	if neg {
+		n = -n
+	}
+	return &n
+}
@@ -0,0 +1,61 @@
+// Package enrichment defines the cached bibliographic metadata produced
+// by cmd/enrich and consumed by the builder. Keeping the shape here, in a
+// package both binaries import, means the builder never needs to know
+// how a Record was produced — only that the cache file exists.
+package enrichment
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Record holds the optional per-manuscript fields cmd/enrich fetches from
+// external providers (CCEL, Wikidata, VIAF). Zero values mean "unknown",
+// not "checked and absent" — every field is omitted from JSON when empty
+// so a partial enrichment doesn't clobber fields a later provider fills in.
+type Record struct {
+	AuthorVIAF     string `json:"author_viaf,omitempty"`
+	AuthorWikidata string `json:"author_wikidata,omitempty"`
+	AuthorBirth    *int   `json:"author_birth,omitempty"`
+	AuthorDeath    *int   `json:"author_death,omitempty"`
+	OriginalLang   string `json:"original_lang,omitempty"`
+	Translator     string `json:"translator,omitempty"`
+	Bio            string `json:"bio,omitempty"`
+}
+
+// IsZero reports whether every field of r is unset.
+func (r Record) IsZero() bool {
+	return r == Record{}
+}
+
+// Cache maps manuscript id to its enrichment Record. It is loaded and
+// saved as a single JSON file so re-running cmd/enrich is idempotent and
+// the builder can rebuild fully offline once the cache exists.
+type Cache map[int64]Record
+
+// LoadCache reads a Cache from path, returning an empty Cache (not an
+// error) if the file doesn't exist yet.
+func LoadCache(path string) (Cache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Cache{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache := make(Cache)
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// Save writes the cache to path as indented JSON.
+func (c Cache) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,276 @@
+// Package refparse turns free-form Bible citation strings ("Jude 4",
+// "Rom 1:1-3,7,9-11", "Matt 5:1-6:4", "1 Cor 13") into structured
+// ParsedRef tuples against a caller-supplied canonical book table.
+//
+// It is deliberately independent of any particular book list or storage
+// format so both the builder and standalone tools (e.g. the --query CLI
+// mode, or a future manuscript-ingestion pipeline) can reuse it.
+package refparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BookInfo is the subset of canonical book metadata refparse needs to
+// resolve a citation: its slug and how many chapters it has. Chapters==1
+// triggers the single-chapter-book quirk (see Parse).
+type BookInfo struct {
+	Slug     string
+	Name     string
+	Chapters int
+}
+
+// ParsedRef is one resolved citation. EndChapter is non-zero only for
+// cross-chapter ranges ("Matt 5:1-6:4"); otherwise the ref is confined to
+// Chapter. VerseStart/VerseEnd are 0 for a whole-chapter reference.
+type ParsedRef struct {
+	BookSlug   string
+	Chapter    int
+	EndChapter int
+	VerseStart int
+	VerseEnd   int
+}
+
+// Table resolves citation text against a fixed set of canonical books
+// plus a configurable alias table (book name variants and abbreviations).
+type Table struct {
+	books   map[string]BookInfo
+	aliases map[string]string // normalized alias -> slug
+}
+
+// NewTable builds a Table from the canonical book list, seeding the alias
+// table with each book's slug, full name, and the common abbreviations
+// known to this package (see commonAbbrevs). Callers can layer in more
+// with AddAlias.
+func NewTable(books []BookInfo) *Table {
+	t := &Table{
+		books:   make(map[string]BookInfo, len(books)),
+		aliases: make(map[string]string),
+	}
+	for _, b := range books {
+		t.books[b.Slug] = b
+		t.AddAlias(b.Slug, b.Slug)
+		t.AddAlias(b.Name, b.Slug)
+		t.AddAlias(strings.ReplaceAll(b.Name, " ", ""), b.Slug)
+	}
+	for slug, aliases := range commonAbbrevs {
+		if _, ok := t.books[slug]; !ok {
+			continue
+		}
+		for _, a := range aliases {
+			t.AddAlias(a, slug)
+		}
+	}
+	return t
+}
+
+// AddAlias registers an additional name/abbreviation for a book slug.
+// Matching is case-insensitive and ordinal-prefix-insensitive (see
+// normalizeKey), so callers don't need to pre-normalize.
+func (t *Table) AddAlias(alias, slug string) {
+	key := normalizeKey(alias)
+	if key == "" {
+		return
+	}
+	t.aliases[key] = slug
+}
+
+// Resolve maps a book name or abbreviation to its canonical slug.
+func (t *Table) Resolve(bookName string) (slug string, ok bool) {
+	slug, ok = t.aliases[normalizeKey(bookName)]
+	return slug, ok
+}
+
+// citationRe splits "<book name> <chapter/verse spec>" — the location
+// spec is whatever trailing run of digits, colons, commas, dashes and
+// spaces closes out the string.
+var citationRe = regexp.MustCompile(`^(.+?)\s+([0-9][0-9:,.\-\x{2013}\s]*)$`)
+
+// Parse resolves a semicolon-separated list of citations, e.g.
+// "Jude 4; Rom 1:1-3,7,9-11; Matt 5:1-6:4". Each comma-separated verse
+// item within a citation becomes its own ParsedRef.
+func (t *Table) Parse(input string) ([]ParsedRef, error) {
+	var out []ParsedRef
+	for _, citation := range strings.Split(input, ";") {
+		citation = strings.TrimSpace(citation)
+		if citation == "" {
+			continue
+		}
+		refs, err := t.parseCitation(citation)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, refs...)
+	}
+	return out, nil
+}
+
+func (t *Table) parseCitation(citation string) ([]ParsedRef, error) {
+	m := citationRe.FindStringSubmatch(citation)
+	if m == nil {
+		return nil, fmt.Errorf("refparse: could not split book name from chapter/verse in %q", citation)
+	}
+	bookPart, locPart := strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+
+	slug, ok := t.Resolve(bookPart)
+	if !ok {
+		return nil, fmt.Errorf("refparse: unknown book %q in %q", bookPart, citation)
+	}
+	book := t.books[slug]
+
+	refs, err := parseLoc(locPart, book.Chapters)
+	if err != nil {
+		return nil, fmt.Errorf("refparse: %q: %w", citation, err)
+	}
+	for i := range refs {
+		refs[i].BookSlug = slug
+	}
+	return refs, nil
+}
+
+var (
+	crossChapterRe = regexp.MustCompile(`^(\d+):(\d+)\s*[-\x{2013}]\s*(\d+):(\d+)$`)
+	chapterVerseRe = regexp.MustCompile(`^(\d+):(.+)$`)
+	bareNumberRe   = regexp.MustCompile(`^(\d+)$`)
+)
+
+// parseLoc parses everything after the book name: a bare chapter number,
+// a single-chapter-book bare verse number, "chapter:verses", or a
+// cross-chapter range "chapter:verse-chapter:verse".
+func parseLoc(loc string, chapters int) ([]ParsedRef, error) {
+	if m := crossChapterRe.FindStringSubmatch(loc); m != nil {
+		ch1, _ := strconv.Atoi(m[1])
+		v1, _ := strconv.Atoi(m[2])
+		ch2, _ := strconv.Atoi(m[3])
+		v2, _ := strconv.Atoi(m[4])
+		return []ParsedRef{{Chapter: ch1, EndChapter: ch2, VerseStart: v1, VerseEnd: v2}}, nil
+	}
+	if m := chapterVerseRe.FindStringSubmatch(loc); m != nil {
+		chapter, _ := strconv.Atoi(m[1])
+		return parseVerseList(chapter, m[2])
+	}
+	if m := bareNumberRe.FindStringSubmatch(loc); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		// Single-chapter books (Jude, Obadiah, Philemon, 2 John, 3 John,
+		// Prayer of Manasseh, ...): a bare number after the book name is
+		// conventionally a verse, not a chapter — "Jude 4" means Jude 1:4.
+		// This is the only sane resolution even for "Jude 1", which is
+		// otherwise ambiguous between "chapter 1" and "verse 1".
+		if chapters == 1 {
+			return []ParsedRef{{Chapter: 1, VerseStart: n, VerseEnd: n}}, nil
+		}
+		return []ParsedRef{{Chapter: n}}, nil
+	}
+	return nil, fmt.Errorf("unrecognized chapter/verse reference %q", loc)
+}
+
+// parseVerseList splits a comma-separated list of verses/verse-ranges
+// within a single chapter, e.g. "1-3, 7, 9-11".
+func parseVerseList(chapter int, verses string) ([]ParsedRef, error) {
+	var refs []ParsedRef
+	for _, part := range strings.Split(verses, ",") {
+		part = strings.TrimSpace(strings.ReplaceAll(part, "–", "-"))
+		if part == "" {
+			continue
+		}
+		if i := strings.Index(part, "-"); i >= 0 {
+			start, err := strconv.Atoi(strings.TrimSpace(part[:i]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid verse range %q", part)
+			}
+			end, err := strconv.Atoi(strings.TrimSpace(part[i+1:]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid verse range %q", part)
+			}
+			refs = append(refs, ParsedRef{Chapter: chapter, VerseStart: start, VerseEnd: end})
+			continue
+		}
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verse %q", part)
+		}
+		refs = append(refs, ParsedRef{Chapter: chapter, VerseStart: v, VerseEnd: v})
+	}
+	return refs, nil
+}
+
+// ordinalWords maps spelled-out and Roman-numeral ordinal prefixes to
+// their digit form, longest first so "third"/"iii" aren't partially
+// matched by a later "i" rule.
+var ordinalWords = strings.NewReplacer(
+	"first ", "1 ",
+	"second ", "2 ",
+	"third ", "3 ",
+	"iii ", "3 ",
+	"ii ", "2 ",
+	"i ", "1 ",
+)
+
+// attachedOrdinalRe catches ordinal+name run together with no space,
+// e.g. "1cor", "2tim", "3jn".
+var attachedOrdinalRe = regexp.MustCompile(`^([123])([a-z].*)$`)
+
+// normalizeKey canonicalizes a book name or abbreviation for alias
+// lookup: lowercase, punctuation stripped, whitespace collapsed, and
+// ordinal prefixes ("First", "I", "1") unified to a leading digit.
+func normalizeKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, ".", "")
+	s = strings.Join(strings.Fields(s), " ")
+	s = ordinalWords.Replace(s)
+	if m := attachedOrdinalRe.FindStringSubmatch(s); m != nil {
+		s = m[1] + " " + m[2]
+	}
+	return s
+}
+
+// commonAbbrevs lists widely-used English, Latin and Greek abbreviations
+// for the books most often cited by patristic authors, keyed by slug.
+// It is not exhaustive — callers can extend it per-Table with AddAlias.
+var commonAbbrevs = map[string][]string{
+	"genesis":            {"gen", "gn", "genese", "genèse", "γεν"},
+	"exodus":             {"ex", "exod", "exo"},
+	"leviticus":          {"lev", "lv"},
+	"numbers":            {"num", "nm", "nu"},
+	"deuteronomy":        {"deut", "dt"},
+	"psalms":             {"ps", "psa", "psalm", "pss"},
+	"proverbs":           {"prov", "prv", "pr"},
+	"isaiah":             {"isa", "is"},
+	"jeremiah":           {"jer", "jr"},
+	"ezekiel":            {"ezek", "eze"},
+	"daniel":             {"dan", "dn"},
+	"obadiah":            {"obad", "ob"},
+	"wisdom":             {"wis", "wisd", "sap"},
+	"sirach":             {"sir", "ecclus"},
+	"matthew":            {"matt", "mt"},
+	"mark":               {"mk", "mrk"},
+	"luke":               {"lk", "luk"},
+	"john":               {"jn", "jhn"},
+	"acts":               {"act"},
+	"romans":             {"rom", "ro"},
+	"1-corinthians":      {"1 cor", "cor"},
+	"2-corinthians":      {"2 cor"},
+	"galatians":          {"gal"},
+	"ephesians":          {"eph"},
+	"philippians":        {"phil", "php"},
+	"colossians":         {"col"},
+	"1-thessalonians":    {"1 thess", "1 th"},
+	"2-thessalonians":    {"2 thess", "2 th"},
+	"1-timothy":          {"1 tim", "1 ti"},
+	"2-timothy":          {"2 tim", "2 ti"},
+	"titus":              {"tit"},
+	"philemon":           {"phlm", "phm"},
+	"hebrews":            {"heb"},
+	"james":              {"jas", "jm"},
+	"1-peter":            {"1 pet", "1 pt"},
+	"2-peter":            {"2 pet", "2 pt"},
+	"1-john":             {"1 jn", "1 jo"},
+	"2-john":             {"2 jn", "2 jo"},
+	"3-john":             {"3 jn", "3 jo"},
+	"jude":               {"jud"},
+	"revelation":         {"rev", "apoc", "apocalypse"},
+	"prayer-of-manasseh": {"pr man", "man", "oratio manasse"},
+}
@@ -0,0 +1,178 @@
+package refparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testTable() *Table {
+	return NewTable([]BookInfo{
+		{Slug: "genesis", Name: "Genesis", Chapters: 50},
+		{Slug: "romans", Name: "Romans", Chapters: 16},
+		{Slug: "1-corinthians", Name: "1 Corinthians", Chapters: 16},
+		{Slug: "matthew", Name: "Matthew", Chapters: 28},
+		{Slug: "jude", Name: "Jude", Chapters: 1},
+		{Slug: "obadiah", Name: "Obadiah", Chapters: 1},
+		{Slug: "philemon", Name: "Philemon", Chapters: 1},
+		{Slug: "2-john", Name: "2 John", Chapters: 1},
+		{Slug: "3-john", Name: "3 John", Chapters: 1},
+		{Slug: "prayer-of-manasseh", Name: "Prayer of Manasseh", Chapters: 1},
+	})
+}
+
+func TestParse(t *testing.T) {
+	table := testTable()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []ParsedRef
+	}{
+		{
+			name:  "book name alias (abbreviation)",
+			input: "Gen 1:1",
+			want:  []ParsedRef{{BookSlug: "genesis", Chapter: 1, VerseStart: 1, VerseEnd: 1}},
+		},
+		{
+			name:  "common Latin/Greek abbreviation",
+			input: "Γεν 1:1",
+			want:  []ParsedRef{{BookSlug: "genesis", Chapter: 1, VerseStart: 1, VerseEnd: 1}},
+		},
+		{
+			name:  "ordinal word prefix",
+			input: "First Corinthians 13:4",
+			want:  []ParsedRef{{BookSlug: "1-corinthians", Chapter: 13, VerseStart: 4, VerseEnd: 4}},
+		},
+		{
+			name:  "ordinal roman numeral prefix",
+			input: "I Cor 13:4",
+			want:  []ParsedRef{{BookSlug: "1-corinthians", Chapter: 13, VerseStart: 4, VerseEnd: 4}},
+		},
+		{
+			name:  "attached ordinal digit prefix",
+			input: "1Cor 13:4",
+			want:  []ParsedRef{{BookSlug: "1-corinthians", Chapter: 13, VerseStart: 4, VerseEnd: 4}},
+		},
+		{
+			name:  "comma and hyphen verse list",
+			input: "Rom 1:1-3,7,9-11",
+			want: []ParsedRef{
+				{BookSlug: "romans", Chapter: 1, VerseStart: 1, VerseEnd: 3},
+				{BookSlug: "romans", Chapter: 1, VerseStart: 7, VerseEnd: 7},
+				{BookSlug: "romans", Chapter: 1, VerseStart: 9, VerseEnd: 11},
+			},
+		},
+		{
+			name:  "en-dash verse range",
+			input: "Rom 1:1–3",
+			want:  []ParsedRef{{BookSlug: "romans", Chapter: 1, VerseStart: 1, VerseEnd: 3}},
+		},
+		{
+			name:  "cross-chapter range",
+			input: "Matt 5:1-6:4",
+			want:  []ParsedRef{{BookSlug: "matthew", Chapter: 5, EndChapter: 6, VerseStart: 1, VerseEnd: 4}},
+		},
+		{
+			name:  "bare chapter number for multi-chapter book",
+			input: "Romans 8",
+			want:  []ParsedRef{{BookSlug: "romans", Chapter: 8}},
+		},
+		{
+			name:  "single-chapter book bare number is a verse",
+			input: "Jude 4",
+			want:  []ParsedRef{{BookSlug: "jude", Chapter: 1, VerseStart: 4, VerseEnd: 4}},
+		},
+		{
+			name:  "single-chapter book ambiguous verse 1",
+			input: "Jude 1",
+			want:  []ParsedRef{{BookSlug: "jude", Chapter: 1, VerseStart: 1, VerseEnd: 1}},
+		},
+		{
+			name:  "single-chapter book Obadiah",
+			input: "Obadiah 10",
+			want:  []ParsedRef{{BookSlug: "obadiah", Chapter: 1, VerseStart: 10, VerseEnd: 10}},
+		},
+		{
+			name:  "single-chapter book Philemon",
+			input: "Philemon 6",
+			want:  []ParsedRef{{BookSlug: "philemon", Chapter: 1, VerseStart: 6, VerseEnd: 6}},
+		},
+		{
+			name:  "single-chapter book 2 John",
+			input: "2 John 7",
+			want:  []ParsedRef{{BookSlug: "2-john", Chapter: 1, VerseStart: 7, VerseEnd: 7}},
+		},
+		{
+			name:  "single-chapter book 3 John",
+			input: "3 John 3",
+			want:  []ParsedRef{{BookSlug: "3-john", Chapter: 1, VerseStart: 3, VerseEnd: 3}},
+		},
+		{
+			name:  "single-chapter book Prayer of Manasseh",
+			input: "Prayer of Manasseh 5",
+			want:  []ParsedRef{{BookSlug: "prayer-of-manasseh", Chapter: 1, VerseStart: 5, VerseEnd: 5}},
+		},
+		{
+			name:  "semicolon-separated citation list",
+			input: "Jude 4; Rom 1:1-3,7",
+			want: []ParsedRef{
+				{BookSlug: "jude", Chapter: 1, VerseStart: 4, VerseEnd: 4},
+				{BookSlug: "romans", Chapter: 1, VerseStart: 1, VerseEnd: 3},
+				{BookSlug: "romans", Chapter: 1, VerseStart: 7, VerseEnd: 7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	table := testTable()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "unknown book", input: "Nonexistentbook 1:1"},
+		{name: "missing location", input: "Romans"},
+		{name: "non-numeric verse", input: "Rom 1:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := table.Parse(tt.input); err == nil {
+				t.Errorf("Parse(%q) returned no error, want one", tt.input)
+			}
+		})
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"1 Corinthians", "1 corinthians"},
+		{"First Corinthians", "1 corinthians"},
+		{"I Corinthians", "1 corinthians"},
+		{"1Cor", "1 cor"},
+		{"2Tim", "2 tim"},
+		{"3Jn", "3 jn"},
+		{"Gen.", "gen"},
+		{"  Genesis  ", "genesis"},
+	}
+	for _, tt := range tests {
+		if got := normalizeKey(tt.in); got != tt.want {
+			t.Errorf("normalizeKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
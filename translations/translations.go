@@ -0,0 +1,183 @@
+// Package translations loads parallel Bible translations from a simple
+// verse-per-row TSV format and makes them available for lookup by book
+// slug, chapter, and verse number.
+//
+// TSV rows have four tab-separated columns: book_slug, chapter, verse, text.
+// Blank lines are skipped; there is no header row.
+package translations
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Meta describes a loaded translation for inclusion in the index payload.
+type Meta struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Language string `json:"language"`
+}
+
+// knownMeta supplies display names and languages for the translation ids
+// called out in the builder's --translations flag. Unrecognized ids still
+// load fine; they just fall back to a bare display name.
+var knownMeta = map[string]struct{ Name, Language string }{
+	"kjv": {"King James Version", "English"},
+	"vul": {"Clementine Vulgate", "Latin"},
+	"grb": {"Greek New Testament (critical text)", "Greek"},
+	"lxx": {"Septuagint (Rahlfs)", "Greek"},
+}
+
+// Store holds one translation's verses, keyed by book slug, chapter, verse.
+type Store struct {
+	ID       string
+	Name     string
+	Language string
+
+	verses map[string]map[int]map[int]string
+}
+
+// Load reads a verse-per-row TSV file and returns the parsed Store.
+// id is the short translation id this store will be registered under
+// (e.g. "kjv", "vul"); it does not need to appear in the file itself.
+func Load(id, path string) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := &Store{ID: id, verses: make(map[string]map[int]map[int]string)}
+	if meta, ok := knownMeta[id]; ok {
+		s.Name, s.Language = meta.Name, meta.Language
+	} else {
+		s.Name = strings.ToUpper(id)
+	}
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		cols := strings.SplitN(line, "\t", 4)
+		if len(cols) != 4 {
+			return nil, fmt.Errorf("%s:%d: expected 4 tab-separated columns, got %d", path, lineNo, len(cols))
+		}
+		slug := cols[0]
+		chapter, err := strconv.Atoi(cols[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid chapter %q: %w", path, lineNo, cols[1], err)
+		}
+		verse, err := strconv.Atoi(cols[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid verse %q: %w", path, lineNo, cols[2], err)
+		}
+		if s.verses[slug] == nil {
+			s.verses[slug] = make(map[int]map[int]string)
+		}
+		if s.verses[slug][chapter] == nil {
+			s.verses[slug][chapter] = make(map[int]string)
+		}
+		s.verses[slug][chapter][verse] = cols[3]
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// HasBook reports whether this translation carries any verses for bookSlug.
+func (s *Store) HasBook(bookSlug string) bool {
+	_, ok := s.verses[bookSlug]
+	return ok
+}
+
+// Verse returns the text for a single verse, or ok=false if this
+// translation has no text for that book/chapter/verse.
+func (s *Store) Verse(bookSlug string, chapter, verse int) (text string, ok bool) {
+	ch, ok := s.verses[bookSlug]
+	if !ok {
+		return "", false
+	}
+	v, ok := ch[chapter]
+	if !ok {
+		return "", false
+	}
+	text, ok = v[verse]
+	return text, ok
+}
+
+// Meta returns the display metadata for this translation.
+func (s *Store) Meta() Meta {
+	return Meta{ID: s.ID, Name: s.Name, Language: s.Language}
+}
+
+// Registry holds every translation the builder loaded via --translations.
+type Registry struct {
+	stores []*Store
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add registers a loaded Store.
+func (r *Registry) Add(s *Store) {
+	r.stores = append(r.stores, s)
+}
+
+// Len reports how many translations are registered.
+func (r *Registry) Len() int {
+	return len(r.stores)
+}
+
+// Metas returns display metadata for every registered translation, in
+// registration order.
+func (r *Registry) Metas() []Meta {
+	if len(r.stores) == 0 {
+		return nil
+	}
+	metas := make([]Meta, len(r.stores))
+	for i, s := range r.stores {
+		metas[i] = s.Meta()
+	}
+	return metas
+}
+
+// VersesFor looks up every verse number in verseNums for bookSlug/chapter
+// across all registered translations and returns them keyed by verse
+// number (as a string, for JSON object keys) and then by translation id.
+// Translations that lack the book, chapter, or verse are simply omitted
+// from that verse's sub-map rather than producing an error. Returns nil
+// if no translation has anything for this chapter.
+func (r *Registry) VersesFor(bookSlug string, chapter int, verseNums []int) map[string]map[string]string {
+	if len(r.stores) == 0 || len(verseNums) == 0 {
+		return nil
+	}
+	out := make(map[string]map[string]string)
+	for _, vn := range verseNums {
+		for _, s := range r.stores {
+			text, ok := s.Verse(bookSlug, chapter, vn)
+			if !ok {
+				continue
+			}
+			key := strconv.Itoa(vn)
+			if out[key] == nil {
+				out[key] = make(map[string]string)
+			}
+			out[key][s.ID] = text
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}